@@ -0,0 +1,14 @@
+// Package database contains the data types shared between readers and dumpers.
+package database
+
+// Row represents a single row of data keyed by column name.
+type Row map[string]interface{}
+
+// Column describes the metadata klepto knows about a table column, as reported by
+// information_schema. Dumpers use this to encode values correctly for their target format.
+type Column struct {
+	Name     string
+	Type     string
+	Nullable bool
+	Charset  string
+}
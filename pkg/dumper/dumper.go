@@ -0,0 +1,99 @@
+// Package dumper defines the interface implemented by every destination driver klepto supports,
+// and the registry used to look drivers up by name.
+package dumper
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/hellofresh/klepto/pkg/database"
+	"github.com/hellofresh/klepto/pkg/reader"
+)
+
+// ConnOpts represents the options available for configuring a dumper's destination connection.
+type ConnOpts struct {
+	// DSN is the connection string used to connect to the destination database.
+	DSN string
+	// Batch is the number of rows written per insert/LOAD DATA batch.
+	Batch int64
+	// MaxConns is the maximum number of open connections to the destination.
+	MaxConns int
+	// MaxIdleConns is the maximum number of idle connections kept open.
+	MaxIdleConns int
+	// MaxConnLifetime is the maximum amount of time a connection may be reused.
+	MaxConnLifetime time.Duration
+
+	// SourceDSN is the connection string of the source database. It's only used by drivers that
+	// need to talk to the source directly rather than through a reader.Reader, such as the
+	// "mysql+binlog" streaming dumper registering itself as a replica.
+	SourceDSN string
+	// ServerID is the replication server-id this process registers as when tailing a source's
+	// binary log. Defaults to a driver-specific constant when zero.
+	ServerID uint32
+
+	// ChunkSize is the number of rows copied per chunk/transaction when walking a table in
+	// primary-key order. Falls back to Batch when zero.
+	ChunkSize int64
+	// MaxLagMillis pauses chunk copying while the destination's replication lag (as reported by
+	// SHOW SLAVE STATUS) exceeds this many milliseconds. Zero disables this check.
+	MaxLagMillis int64
+	// MaxLoad pauses chunk copying while any of the given SHOW GLOBAL STATUS metrics exceed
+	// their threshold, gh-ost style: "Threads_running=25,Threads_connected=500".
+	MaxLoad string
+	// MaxQPS caps the number of chunks submitted per second. Zero disables this check.
+	MaxQPS int
+	// ThrottleQuery is a user-provided query run before each chunk; a missing row or falsy first
+	// column pauses copying until it passes.
+	ThrottleQuery string
+
+	// InsertStrategy selects how dumped rows are written to the destination. Supported values
+	// are driver-specific; the mysql driver accepts "load-data" (the default), "replace",
+	// "upsert" and "insert-ignore".
+	InsertStrategy string
+
+	// TLSConfig, when set, is used to encrypt the connection to the destination instead of the
+	// driver's own built-in TLS handling. Lets callers pin a CA, present a client certificate, or
+	// talk to managed MySQL (RDS/Aurora/CloudSQL) that requires custom verification.
+	TLSConfig *tls.Config
+	// DialContext, when set, replaces the driver's default TCP dialer. Used to route connections
+	// through e.g. an AWS RDS IAM proxy or a GCP Cloud SQL dialer instead of a plain host:port.
+	DialContext func(ctx context.Context, addr string) (net.Conn, error)
+	// BeforeConnect, when set, is called before every new connection and should return the
+	// password to use for it, so credentials that rotate (IAM auth tokens, short-lived secrets)
+	// don't need to be baked into DSN.
+	BeforeConnect func(ctx context.Context) (string, error)
+}
+
+// Dumper writes the structure and data produced by a reader.Reader to a destination.
+type Dumper interface {
+	// DumpStructure writes the given DDL statement(s) to the destination.
+	DumpStructure(sql string) error
+	// DumpTable streams rows for the given table to the destination.
+	DumpTable(tableName string, rowChan <-chan database.Row) error
+	// Close releases any resources held by the dumper.
+	Close() error
+}
+
+// Driver is implemented by each supported destination database driver and registered under a
+// name via Register, so it can be selected by the CLI.
+type Driver interface {
+	// IsSupported checks if the given dsn connection string is supported by this driver.
+	IsSupported(dsn string) bool
+	// NewConnection creates a new destination connection and returns a Dumper for it.
+	NewConnection(opts ConnOpts, rdr reader.Reader) (Dumper, error)
+}
+
+var drivers = make(map[string]Driver)
+
+// Register registers a driver under the given name so it can be looked up later.
+func Register(name string, driver Driver) {
+	drivers[name] = driver
+}
+
+// Get returns the driver registered under name, and whether one was found.
+func Get(name string) (Driver, bool) {
+	d, ok := drivers[name]
+	return d, ok
+}
@@ -0,0 +1,38 @@
+// Package engine adapts a database-specific dumper implementation into a dumper.Dumper.
+package engine
+
+import (
+	"github.com/hellofresh/klepto/pkg/database"
+	"github.com/hellofresh/klepto/pkg/dumper"
+	"github.com/hellofresh/klepto/pkg/reader"
+)
+
+// target is implemented by database-specific dumpers (e.g. mysql's myDumper) and wrapped by
+// New into a dumper.Dumper.
+type target interface {
+	DumpStructure(sql string) error
+	DumpTable(tableName string, rowChan <-chan database.Row) error
+	Close() error
+}
+
+type engine struct {
+	reader reader.Reader
+	target target
+}
+
+// New wires a reader.Reader and a database-specific target together into a dumper.Dumper.
+func New(rdr reader.Reader, t target) dumper.Dumper {
+	return &engine{reader: rdr, target: t}
+}
+
+func (e *engine) DumpStructure(sql string) error {
+	return e.target.DumpStructure(sql)
+}
+
+func (e *engine) DumpTable(tableName string, rowChan <-chan database.Row) error {
+	return e.target.DumpTable(tableName, rowChan)
+}
+
+func (e *engine) Close() error {
+	return e.target.Close()
+}
@@ -1,9 +1,7 @@
 package mysql
 
 import (
-	"bytes"
 	"database/sql"
-	"encoding/csv"
 	"fmt"
 	"github.com/go-sql-driver/mysql"
 	log "github.com/sirupsen/logrus"
@@ -11,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/hellofresh/klepto/pkg/database"
 	"github.com/hellofresh/klepto/pkg/dumper"
@@ -20,27 +19,45 @@ import (
 
 const (
 	null = "NULL"
+
+	// throttleCheckInterval is how long DumpTable waits between re-checking a throttler that
+	// reported it should hold off, before trying the next chunk again.
+	throttleCheckInterval = time.Second
 )
 
 type (
 	myDumper struct {
 		conn                *sql.DB
 		reader              reader.Reader
+		opts                dumper.ConnOpts
 		setGlobalInline     sync.Once
 		disableGlobalInline bool
 		batch               int64
+		throttler           Throttler
 	}
 )
 
 // NewDumper returns a new mysql dumper.
-func NewDumper(conn *sql.DB, rdr reader.Reader, batch int64) dumper.Dumper {
+func NewDumper(conn *sql.DB, rdr reader.Reader, opts dumper.ConnOpts) dumper.Dumper {
 	return engine.New(rdr, &myDumper{
-		conn:   conn,
-		reader: rdr,
-		batch:  batch,
+		conn:      conn,
+		reader:    rdr,
+		opts:      opts,
+		batch:     opts.Batch,
+		throttler: newReplicaLagThrottler(conn, opts),
 	})
 }
 
+// chunkSize returns the number of rows copied per chunk/transaction: opts.ChunkSize when set,
+// falling back to opts.Batch so existing configs keep working unchanged.
+func (d *myDumper) chunkSize() int64 {
+	if d.opts.ChunkSize > 0 {
+		return d.opts.ChunkSize
+	}
+
+	return d.batch
+}
+
 // DumpStructure dump the mysql database structure.
 func (d *myDumper) DumpStructure(sql string) error {
 	if _, err := d.conn.Exec(sql); err != nil {
@@ -50,7 +67,10 @@ func (d *myDumper) DumpStructure(sql string) error {
 	return nil
 }
 
-// DumpTable dumps a mysql table.
+// DumpTable dumps a mysql table. When a primary key (or unique index) can be discovered for
+// tableName, it's copied as a gh-ost-style chunked walk straight off the source via
+// reader.ReadTableChunk, throttling between chunks; otherwise it falls back to draining rowChan
+// batch by batch, still throttled, exactly as before chunking existed.
 func (d *myDumper) DumpTable(tableName string, rowChan <-chan database.Row) error {
 	var err error
 	d.setGlobalInline.Do(func() {
@@ -73,25 +93,174 @@ func (d *myDumper) DumpTable(tableName string, rowChan <-chan database.Row) erro
 		return err
 	}
 
-	batch := d.batch
+	chunkKey, err := d.discoverChunkKey(tableName)
+	if err != nil {
+		log.WithError(err).WithField("table", tableName).Warn("failed to discover chunking key, falling back to unchunked copy")
+	}
+	if len(chunkKey) > 0 {
+		return d.dumpTableChunked(tableName, chunkKey)
+	}
+
+	return d.dumpTableFromChannel(tableName, rowChan)
+}
+
+// dumpTableChunked walks tableName in ascending chunkKey order (chunkKey may be a composite
+// key, walked as a tuple so no row is skipped when two rows share a leading column value),
+// pulling each chunk directly from the source via reader.ReadTableChunk and feeding it through
+// the configured insert strategy, throttling before every chunk.
+func (d *myDumper) dumpTableChunked(tableName string, chunkKey []string) error {
+	log.WithFields(log.Fields{"table": tableName, "key": chunkKey}).Debug("chunking table by key")
+
+	chunkSize := d.chunkSize()
+	var after []interface{}
+	var total int64
+	for {
+		if err := d.waitForThrottle(tableName); err != nil {
+			return fmt.Errorf("failed while throttling: %w", err)
+		}
+
+		rows, hasMore, err := d.reader.ReadTableChunk(tableName, chunkKey, after, chunkSize)
+		if err != nil {
+			return fmt.Errorf("failed to read chunk: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		inserted, err := d.insertChunk(tableName, rows)
+		if err != nil {
+			return fmt.Errorf("failed to insert chunk: %w", err)
+		}
+		total += inserted
+
+		log.WithFields(log.Fields{"table": tableName, "inserted": total}).Debug("inserted rows")
+
+		if !hasMore {
+			return nil
+		}
+
+		last := rows[len(rows)-1]
+		after = make([]interface{}, len(chunkKey))
+		for i, col := range chunkKey {
+			after[i] = last[col]
+		}
+	}
+}
+
+// insertChunk writes a chunk already read into memory through the usual insert pipeline, inside
+// its own transaction, by replaying it over a small closed channel so it can reuse insertRows
+// exactly as the unchunked path does.
+func (d *myDumper) insertChunk(tableName string, rows []database.Row) (int64, error) {
+	txn, err := d.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open transaction: %w", err)
+	}
+
+	rowChan := make(chan database.Row, len(rows))
+	for _, row := range rows {
+		rowChan <- row
+	}
+	close(rowChan)
+
+	inserted, err := d.insertRows(txn, tableName, rowChan, int64(len(rows)))
+	if err != nil {
+		if rbErr := txn.Rollback(); rbErr != nil {
+			log.WithError(rbErr).Error("failed to rollback")
+		}
+		return 0, fmt.Errorf("failed to insert rows: %w", err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// dumpTableFromChannel drains rowChan batch by batch, throttling between batches. It's the
+// fallback used when no chunk key could be discovered for tableName.
+func (d *myDumper) dumpTableFromChannel(tableName string, rowChan <-chan database.Row) error {
+	batch := d.chunkSize()
 	inserted := batch
+	var err error
 	for inserted == batch {
+		if err := d.waitForThrottle(tableName); err != nil {
+			return fmt.Errorf("failed while throttling: %w", err)
+		}
+
 		inserted, err = d.trunkInsert(tableName, rowChan, batch)
 		if err != nil {
-			log.Panic(err)
+			return fmt.Errorf("failed to insert batch: %w", err)
 		}
 	}
 
 	return nil
 }
 
+// waitForThrottle blocks until the configured Throttler reports it's safe to submit the next
+// chunk, re-checking every throttleCheckInterval and logging why it's waiting.
+func (d *myDumper) waitForThrottle(tableName string) error {
+	if d.throttler == nil {
+		return nil
+	}
+
+	for {
+		shouldThrottle, reason, err := d.throttler.ShouldThrottle()
+		if err != nil {
+			return err
+		}
+		if !shouldThrottle {
+			return nil
+		}
+
+		log.WithFields(log.Fields{"table": tableName, "reason": reason}).Debug("throttling before next chunk")
+		time.Sleep(throttleCheckInterval)
+	}
+}
+
+// discoverChunkKey looks up the table's primary key (or, failing that, its first unique index)
+// via information_schema, returning every column of that index in SEQ_IN_INDEX order. A
+// composite key's columns must all be returned and walked together as a tuple cursor - chunking
+// on only its first column would silently drop any row that shares that column's value with the
+// last row of a chunk but sorts before it on the remaining columns. Returns a nil slice if
+// tableName has no primary or unique index.
+func (d *myDumper) discoverChunkKey(tableName string) ([]string, error) {
+	rows, err := d.conn.Query(
+		`SELECT COLUMN_NAME FROM information_schema.STATISTICS
+		 WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND NON_UNIQUE = 0
+		   AND INDEX_NAME = (
+		       SELECT INDEX_NAME FROM information_schema.STATISTICS
+		       WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND NON_UNIQUE = 0
+		       ORDER BY INDEX_NAME = 'PRIMARY' DESC, INDEX_NAME
+		       LIMIT 1
+		   )
+		 ORDER BY SEQ_IN_INDEX`,
+		tableName, tableName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query information_schema.statistics: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("failed to scan chunk key column: %w", err)
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, rows.Err()
+}
+
 func (d *myDumper) trunkInsert(tableName string, rowChan <-chan database.Row, batch int64) (int64, error) {
 	txn, err := d.conn.Begin()
 	if err != nil {
 		return 0, fmt.Errorf("failed to open transaction: %w", err)
 	}
 
-	insertedRows, err := d.insertIntoTable(txn, tableName, rowChan, batch)
+	insertedRows, err := d.insertRows(txn, tableName, rowChan, batch)
 	if err != nil {
 		defer func() {
 			if err := txn.Rollback(); err != nil {
@@ -143,13 +312,24 @@ func (d *myDumper) insertIntoTable(txn *sql.Tx, tableName string, rowChan <-chan
 		return 0, fmt.Errorf("failed to get columns: %w", err)
 	}
 
+	columnTypes, err := d.reader.GetColumnTypes(tableName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get column types: %w", err)
+	}
+	columnsByName := make(map[string]database.Column, len(columnTypes))
+	for _, c := range columnTypes {
+		columnsByName[c.Name] = c
+	}
+
 	columnsQuoted := make([]string, len(columns))
 	for i, column := range columns {
 		columnsQuoted[i] = d.quoteIdentifier(column)
 	}
 
+	// ESCAPED BY is left at its default ('\\') so the \N NULL marker mysqlEncoder emits is
+	// recognised; overriding it here previously made every NULL load as the literal string "\N".
 	query := fmt.Sprintf(
-		"LOAD DATA CONCURRENT LOCAL INFILE 'Reader::%s' INTO TABLE %s FIELDS TERMINATED BY ',' ENCLOSED BY '\"' ESCAPED BY '\"' (%s)",
+		"LOAD DATA CONCURRENT LOCAL INFILE 'Reader::%s' INTO TABLE %s FIELDS TERMINATED BY ',' ENCLOSED BY '\"' (%s)",
 		tableName,
 		d.quoteIdentifier(tableName),
 		strings.Join(columnsQuoted, ","),
@@ -157,15 +337,16 @@ func (d *myDumper) insertIntoTable(txn *sql.Tx, tableName string, rowChan <-chan
 
 	fmt.Println("LOAD DATA : ", query)
 
-	// Write all rows as csv to the pipe
+	// Write all rows to the pipe, one LOAD DATA line per row. We build lines by hand rather than
+	// via encoding/csv: its RFC4180 quote-doubling doesn't understand LOAD DATA's FIELDS ESCAPED
+	// BY scheme, so the two would fight over how a quote or backslash inside a field gets encoded.
 	rowReader, rowWriter := io.Pipe()
 	var inserted int64
+	var encodeErr error
+	encoder := mysqlEncoder{}
 	go func(writer *io.PipeWriter) {
 		defer writer.Close()
 
-		w := csv.NewWriter(writer)
-		defer w.Flush()
-
 		for {
 			row, more := <-rowChan
 			if !more {
@@ -175,20 +356,15 @@ func (d *myDumper) insertIntoTable(txn *sql.Tx, tableName string, rowChan <-chan
 			// Put the data in the correct order and format
 			rowValues := make([]string, len(columns))
 			for i, col := range columns {
-				switch v := row[col].(type) {
-				case nil:
-					rowValues[i] = null
-				case string:
-					rowValues[i] = row[col].(string)
-				case []uint8:
-					rowValues[i] = string(row[col].([]uint8))
-				default:
-					log.WithField("type", v).Info("we have an unhandled type. attempting to convert to a string \n")
-					rowValues[i] = row[col].(string)
+				field, err := encoder.EncodeCSVField(columnsByName[col], row[col])
+				if err != nil {
+					encodeErr = fmt.Errorf("failed to encode column %q: %w", col, err)
+					return
 				}
+				rowValues[i] = field
 			}
 
-			if err := w.Write(rowValues); err != nil {
+			if _, err := io.WriteString(writer, strings.Join(rowValues, ",")+"\n"); err != nil {
 				log.WithError(err).Error("error writing record to mysql")
 			}
 
@@ -211,72 +387,8 @@ func (d *myDumper) insertIntoTable(txn *sql.Tx, tableName string, rowChan <-chan
 		return 0, fmt.Errorf("failed to execute query: %w", err)
 	}
 
-	return inserted, nil
-}
-
-func (d *myDumper) insertIntoTableReplace(txn *sql.Tx, tableName string, rowChan <-chan database.Row, batch int64) (int64, error) {
-	columns, err := d.reader.GetColumns(tableName)
-	if err != nil {
-		return 0, fmt.Errorf("failed to get columns: %w", err)
-	}
-
-	columnsQuoted := make([]string, len(columns))
-	for i, column := range columns {
-		columnsQuoted[i] = d.quoteIdentifier(column)
-	}
-
-	buf := bytes.Buffer{}
-	BufSizeLimit := 1 * 1024 * 1024 // 1MB. TODO parameterize it
-	BufSizeLimitDelta := 1024
-	buf.Grow(BufSizeLimit + BufSizeLimitDelta)
-
-	buf.WriteString(fmt.Sprintf(`replace into %s values `,
-		tableName))
-
-	var inserted int64
-	for {
-		row, more := <-rowChan
-		if !more {
-			break
-		}
-
-		// Put the data in the correct order and format
-		rowValues := make([]string, len(columns))
-		for i, col := range columns {
-			switch v := row[col].(type) {
-			case nil:
-				rowValues[i] = null
-			case string:
-				rowValues[i] = row[col].(string)
-			case []uint8:
-				rowValues[i] = string(row[col].([]uint8))
-			default:
-				log.WithField("type", v).Info("we have an unhandled type. attempting to convert to a string \n")
-				rowValues[i] = row[col].(string)
-			}
-		}
-		if inserted != 0 {
-			buf.WriteString(",")
-		}
-		buf.WriteString("(" + strings.Join(rowValues, ",") + ")")
-
-		atomic.AddInt64(&inserted, 1)
-		if inserted >= batch {
-			break
-		}
-	}
-
-	if inserted == 0 {
-		return 0, nil
-	}
-
-	//fmt.Println("query", buf.String())
-	if _, err := txn.Exec("SET foreign_key_checks = 0;"); err != nil {
-		return 0, fmt.Errorf("failed to disable foreign key checks: %w", err)
-	}
-
-	if _, err := txn.Exec(buf.String()); err != nil {
-		return 0, fmt.Errorf("failed to execute query: %w", err)
+	if encodeErr != nil {
+		return 0, encodeErr
 	}
 
 	return inserted, nil
@@ -0,0 +1,157 @@
+package mysql
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/hellofresh/klepto/pkg/database"
+)
+
+func TestMysqlEncoder_EncodeCSVField(t *testing.T) {
+	loc := time.UTC
+	ts := time.Date(2023, 5, 17, 13, 45, 30, 0, loc)
+
+	tests := []struct {
+		name    string
+		col     database.Column
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "null on nullable column",
+			col:   database.Column{Name: "deleted_at", Type: "DATETIME", Nullable: true},
+			value: nil,
+			want:  `\N`,
+		},
+		{
+			name:    "null on non-nullable column",
+			col:     database.Column{Name: "id", Type: "BIGINT", Nullable: false},
+			value:   nil,
+			wantErr: true,
+		},
+		{
+			name:  "varchar string, including a literal NULL value",
+			col:   database.Column{Name: "status", Type: "VARCHAR", Nullable: false},
+			value: "NULL",
+			want:  `"NULL"`,
+		},
+		{
+			name:  "blob bytes",
+			col:   database.Column{Name: "payload", Type: "BLOB", Nullable: true, Charset: "binary"},
+			value: []byte{0x01, 0x02, 0xff},
+			want:  `"` + string([]byte{0x01, 0x02, 0xff}) + `"`,
+		},
+		{
+			name:  "sql.RawBytes",
+			col:   database.Column{Name: "payload", Type: "TEXT", Nullable: true},
+			value: sql.RawBytes("raw"),
+			want:  `"raw"`,
+		},
+		{
+			name:  "bool true",
+			col:   database.Column{Name: "active", Type: "TINYINT", Nullable: false},
+			value: true,
+			want:  `"1"`,
+		},
+		{
+			name:  "bool false",
+			col:   database.Column{Name: "active", Type: "TINYINT", Nullable: false},
+			value: false,
+			want:  `"0"`,
+		},
+		{
+			name:  "int64",
+			col:   database.Column{Name: "id", Type: "BIGINT", Nullable: false},
+			value: int64(42),
+			want:  `"42"`,
+		},
+		{
+			name:  "float64",
+			col:   database.Column{Name: "price", Type: "DOUBLE", Nullable: false},
+			value: 19.99,
+			want:  `"19.99"`,
+		},
+		{
+			name:  "date",
+			col:   database.Column{Name: "born_on", Type: "DATE", Nullable: false},
+			value: ts,
+			want:  `"2023-05-17"`,
+		},
+		{
+			name:  "time",
+			col:   database.Column{Name: "starts_at", Type: "TIME", Nullable: false},
+			value: ts,
+			want:  `"13:45:30"`,
+		},
+		{
+			name:  "datetime",
+			col:   database.Column{Name: "created_at", Type: "DATETIME", Nullable: false},
+			value: ts,
+			want:  `"2023-05-17 13:45:30"`,
+		},
+		{
+			name:  "timestamp",
+			col:   database.Column{Name: "updated_at", Type: "TIMESTAMP", Nullable: false},
+			value: ts,
+			want:  `"2023-05-17 13:45:30"`,
+		},
+		{
+			name:  "embedded backslash",
+			col:   database.Column{Name: "path", Type: "VARCHAR", Nullable: false},
+			value: `C:\klepto\data`,
+			want:  `"C:\\klepto\\data"`,
+		},
+		{
+			name:  "embedded double quote",
+			col:   database.Column{Name: "note", Type: "VARCHAR", Nullable: false},
+			value: `she said "hi"`,
+			want:  `"she said \"hi\""`,
+		},
+		{
+			name:  "embedded tab and newline",
+			col:   database.Column{Name: "note", Type: "TEXT", Nullable: false},
+			value: "a\tb\nc",
+			want:  `"a\tb\nc"`,
+		},
+		{
+			name:  "embedded carriage return",
+			col:   database.Column{Name: "note", Type: "TEXT", Nullable: false},
+			value: "a\rb",
+			want:  `"a\rb"`,
+		},
+		{
+			name:  "value that looks like the NULL marker is still quoted",
+			col:   database.Column{Name: "note", Type: "VARCHAR", Nullable: false},
+			value: `\N`,
+			want:  `"\\N"`,
+		},
+		{
+			name:    "unsupported type",
+			col:     database.Column{Name: "weird", Type: "GEOMETRY", Nullable: true},
+			value:   struct{}{},
+			wantErr: true,
+		},
+	}
+
+	enc := mysqlEncoder{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := enc.EncodeCSVField(tt.col, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got field %q", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package mysql
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/hellofresh/klepto/pkg/database"
+)
+
+// Supported values for dumper.ConnOpts.InsertStrategy.
+const (
+	// InsertStrategyLoadData streams rows through LOAD DATA LOCAL INFILE. This is the default
+	// and by far the fastest option, but requires the destination table to be empty (or at
+	// least free of conflicting keys) since it performs a plain INSERT under the hood.
+	InsertStrategyLoadData = "load-data"
+	// InsertStrategyReplace emits REPLACE INTO statements, so existing rows sharing a unique key
+	// are deleted and re-inserted.
+	InsertStrategyReplace = "replace"
+	// InsertStrategyUpsert emits INSERT ... ON DUPLICATE KEY UPDATE statements, so existing rows
+	// are updated in place rather than deleted and re-inserted. Use this to refresh an
+	// already-populated staging database without dropping it first.
+	InsertStrategyUpsert = "upsert"
+	// InsertStrategyInsertIgnore emits INSERT IGNORE statements, silently skipping rows that
+	// would violate a unique key rather than touching the existing row.
+	InsertStrategyInsertIgnore = "insert-ignore"
+)
+
+// insertRows writes one chunk of rows from rowChan using the strategy configured via
+// opts.InsertStrategy, defaulting to InsertStrategyLoadData when unset.
+func (d *myDumper) insertRows(txn *sql.Tx, tableName string, rowChan <-chan database.Row, batch int64) (int64, error) {
+	switch d.opts.InsertStrategy {
+	case "", InsertStrategyLoadData:
+		return d.insertIntoTable(txn, tableName, rowChan, batch)
+	case InsertStrategyReplace:
+		return d.insertIntoTableVerb(txn, "REPLACE", tableName, rowChan, batch)
+	case InsertStrategyInsertIgnore:
+		return d.insertIntoTableVerb(txn, "INSERT IGNORE", tableName, rowChan, batch)
+	case InsertStrategyUpsert:
+		return d.insertIntoTableUpsert(txn, tableName, rowChan, batch)
+	default:
+		return 0, fmt.Errorf("unknown insert strategy %q", d.opts.InsertStrategy)
+	}
+}
+
+// insertIntoTableVerb builds a single "<verb> INTO tbl (cols) VALUES (...), (...)" statement out
+// of up to batch rows from rowChan. It backs both InsertStrategyReplace and
+// InsertStrategyInsertIgnore, which only differ in the SQL verb used.
+func (d *myDumper) insertIntoTableVerb(txn *sql.Tx, verb, tableName string, rowChan <-chan database.Row, batch int64) (int64, error) {
+	columns, err := d.reader.GetColumns(tableName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	columnsQuoted := make([]string, len(columns))
+	for i, column := range columns {
+		columnsQuoted[i] = d.quoteIdentifier(column)
+	}
+
+	buf := bytes.Buffer{}
+	buf.WriteString(fmt.Sprintf(
+		"%s INTO %s (%s) VALUES ",
+		verb,
+		d.quoteIdentifier(tableName),
+		strings.Join(columnsQuoted, ","),
+	))
+
+	var inserted int64
+	for {
+		row, more := <-rowChan
+		if !more {
+			break
+		}
+
+		rowValues, err := d.valuesFromRow(columns, row)
+		if err != nil {
+			return 0, err
+		}
+
+		if inserted != 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("(" + strings.Join(rowValues, ",") + ")")
+
+		atomic.AddInt64(&inserted, 1)
+		if inserted >= batch {
+			break
+		}
+	}
+
+	if inserted == 0 {
+		return 0, nil
+	}
+
+	if _, err := txn.Exec("SET foreign_key_checks = 0;"); err != nil {
+		return 0, fmt.Errorf("failed to disable foreign key checks: %w", err)
+	}
+
+	if _, err := txn.Exec(buf.String()); err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// insertIntoTableUpsert builds a single "INSERT INTO tbl (cols) VALUES (...), (...)
+// ON DUPLICATE KEY UPDATE col = VALUES(col), ..." statement, so re-running a dump against an
+// already-populated staging database updates existing rows instead of failing or skipping them.
+func (d *myDumper) insertIntoTableUpsert(txn *sql.Tx, tableName string, rowChan <-chan database.Row, batch int64) (int64, error) {
+	columns, err := d.reader.GetColumns(tableName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	primaryKey, err := d.discoverPrimaryKeyColumns(tableName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to discover primary key: %w", err)
+	}
+	primaryKeySet := make(map[string]struct{}, len(primaryKey))
+	for _, col := range primaryKey {
+		primaryKeySet[col] = struct{}{}
+	}
+
+	columnsQuoted := make([]string, len(columns))
+	for i, column := range columns {
+		columnsQuoted[i] = d.quoteIdentifier(column)
+	}
+
+	var updates []string
+	for _, column := range columns {
+		if _, isPrimaryKey := primaryKeySet[column]; isPrimaryKey {
+			continue
+		}
+
+		quoted := d.quoteIdentifier(column)
+		updates = append(updates, fmt.Sprintf("%s = VALUES(%s)", quoted, quoted))
+	}
+
+	buf := bytes.Buffer{}
+	buf.WriteString(fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES ",
+		d.quoteIdentifier(tableName),
+		strings.Join(columnsQuoted, ","),
+	))
+
+	var inserted int64
+	for {
+		row, more := <-rowChan
+		if !more {
+			break
+		}
+
+		rowValues, err := d.valuesFromRow(columns, row)
+		if err != nil {
+			return 0, err
+		}
+
+		if inserted != 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("(" + strings.Join(rowValues, ",") + ")")
+
+		atomic.AddInt64(&inserted, 1)
+		if inserted >= batch {
+			break
+		}
+	}
+
+	if inserted == 0 {
+		return 0, nil
+	}
+
+	if len(updates) > 0 {
+		buf.WriteString(" ON DUPLICATE KEY UPDATE " + strings.Join(updates, ","))
+	}
+
+	if _, err := txn.Exec("SET foreign_key_checks = 0;"); err != nil {
+		return 0, fmt.Errorf("failed to disable foreign key checks: %w", err)
+	}
+
+	if _, err := txn.Exec(buf.String()); err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// discoverPrimaryKeyColumns returns tableName's primary key columns, in index order, via
+// information_schema. Returns an empty slice for tables without a primary key.
+func (d *myDumper) discoverPrimaryKeyColumns(tableName string) ([]string, error) {
+	rows, err := d.conn.Query(
+		`SELECT COLUMN_NAME FROM information_schema.STATISTICS
+		 WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND INDEX_NAME = 'PRIMARY'
+		 ORDER BY SEQ_IN_INDEX`,
+		tableName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query information_schema.statistics: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("failed to scan primary key column: %w", err)
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, rows.Err()
+}
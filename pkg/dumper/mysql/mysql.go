@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -25,6 +26,17 @@ func (m *driver) IsSupported(dsn string) bool {
 
 // NewConnection creates a new mysql connection and retrieves a new mysql dumper.
 func (m *driver) NewConnection(opts dumper.ConnOpts, rdr reader.Reader) (dumper.Dumper, error) {
+	conn, err := openConnection(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDumper(conn, rdr, opts), nil
+}
+
+// openConnection parses opts.DSN, forces MultiStatements on (LOAD DATA relies on it) and opens
+// the resulting *sql.DB. Shared by every driver registered from this package.
+func openConnection(opts dumper.ConnOpts) (*sql.DB, error) {
 	dsnCfg, err := mysql.ParseDSN(opts.DSN)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse mysql dsn: %w", err)
@@ -36,16 +48,42 @@ func (m *driver) NewConnection(opts dumper.ConnOpts, rdr reader.Reader) (dumper.
 		dsnCfg.MultiStatements = true
 	}
 
-	conn, err := sql.Open("mysql", dsnCfg.FormatDSN())
+	if opts.TLSConfig != nil {
+		tlsConfigName := "klepto-" + dsnCfg.Addr
+		if err := mysql.RegisterTLSConfig(tlsConfigName, opts.TLSConfig); err != nil {
+			return nil, fmt.Errorf("failed to register mysql tls config: %w", err)
+		}
+		dsnCfg.TLSConfig = tlsConfigName
+	}
+
+	if opts.DialContext != nil {
+		dialerName := "klepto-" + dsnCfg.Addr
+		mysql.RegisterDialContext(dialerName, opts.DialContext)
+		dsnCfg.Net = dialerName
+	}
+
+	if opts.BeforeConnect != nil {
+		dsnCfg.BeforeConnect = func(ctx context.Context, cfg *mysql.Config) error {
+			password, err := opts.BeforeConnect(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to refresh mysql credentials: %w", err)
+			}
+			cfg.Passwd = password
+			return nil
+		}
+	}
+
+	connector, err := mysql.NewConnector(dsnCfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open mysql connection: %w", err)
+		return nil, fmt.Errorf("failed to build mysql connector: %w", err)
 	}
 
+	conn := sql.OpenDB(connector)
 	conn.SetMaxOpenConns(opts.MaxConns)
 	conn.SetMaxIdleConns(opts.MaxIdleConns)
 	conn.SetConnMaxLifetime(opts.MaxConnLifetime)
 
-	return NewDumper(conn, rdr, opts.Batch), nil
+	return conn, nil
 }
 
 func init() {
@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hellofresh/klepto/pkg/database"
+)
+
+// quoteValue renders v as a MySQL SQL literal suitable for direct inclusion in a statement. It's
+// the shared quoting path for the replace/upsert/insert-ignore strategies; LOAD DATA doesn't go
+// through the query string at all, so it keeps its own CSV escaping.
+func quoteValue(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return null, nil
+	case string:
+		return quoteString(val), nil
+	case []byte:
+		return quoteString(string(val)), nil
+	case sql.RawBytes:
+		return quoteString(string(val)), nil
+	case bool:
+		if val {
+			return "1", nil
+		}
+		return "0", nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprintf("%v", val), nil
+	case time.Time:
+		return quoteString(val.Format("2006-01-02 15:04:05.999999")), nil
+	case json.RawMessage:
+		return quoteString(string(val)), nil
+	default:
+		encoded, err := json.Marshal(val)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode value of type %T as JSON: %w", val, err)
+		}
+		return quoteString(string(encoded)), nil
+	}
+}
+
+// quoteString wraps s in single quotes, escaping backslashes, quotes and control characters the
+// way MySQL expects inside a quoted string literal.
+func quoteString(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`'`, `\'`,
+		"\x00", `\0`,
+		"\n", `\n`,
+		"\r", `\r`,
+	)
+
+	return "'" + replacer.Replace(s) + "'"
+}
+
+// valuesFromRow quotes every column of row, in column order, for inclusion in an INSERT/REPLACE
+// VALUES tuple.
+func (d *myDumper) valuesFromRow(columns []string, row database.Row) ([]string, error) {
+	values := make([]string, len(columns))
+	for i, col := range columns {
+		v, err := quoteValue(row[col])
+		if err != nil {
+			return nil, fmt.Errorf("failed to quote column %q: %w", col, err)
+		}
+		values[i] = v
+	}
+
+	return values, nil
+}
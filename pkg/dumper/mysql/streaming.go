@@ -0,0 +1,478 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/hellofresh/klepto/pkg/database"
+	"github.com/hellofresh/klepto/pkg/dumper"
+	"github.com/hellofresh/klepto/pkg/reader"
+)
+
+// binlogStateTable stores the last applied source position on the destination, so a restarted
+// stream resumes from where it left off instead of re-applying (or skipping) events.
+const binlogStateTable = "_klepto_binlog_state"
+
+// defaultServerID is used to register as a replica with the source when opts.ServerID is unset.
+// It's arbitrary but should be unique among anything else replicating from the same source.
+const defaultServerID = 2727
+
+type binlogDriver struct{}
+
+// IsSupported checks if the given dsn connection string is supported.
+func (b *binlogDriver) IsSupported(dsn string) bool {
+	return (&driver{}).IsSupported(dsn)
+}
+
+// NewConnection creates a new mysql connection and retrieves a streaming mysql dumper.
+func (b *binlogDriver) NewConnection(opts dumper.ConnOpts, rdr reader.Reader) (dumper.Dumper, error) {
+	conn, err := openConnection(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStreamingDumper(conn, rdr, opts), nil
+}
+
+func init() {
+	dumper.Register("mysql+binlog", &binlogDriver{})
+}
+
+// streamingDumper performs the usual one-shot batched load via the embedded myDumper, then,
+// once Stream is called, stays connected to the source as a fake replica and continuously
+// applies WRITE_ROWS/UPDATE_ROWS/DELETE_ROWS events for every table it dumped.
+type streamingDumper struct {
+	*myDumper
+	opts     dumper.ConnOpts
+	syncer   *replication.BinlogSyncer
+	tablesMu sync.Mutex
+	tables   map[string]struct{}
+
+	seedOnce sync.Once
+	seedErr  error
+}
+
+// NewStreamingDumper returns a new mysql dumper whose Stream method keeps the destination
+// continuously in sync with the source's binary log after the initial bulk load. The returned
+// value is the *streamingDumper itself, so callers can reach Stream with a type assertion.
+func NewStreamingDumper(conn *sql.DB, rdr reader.Reader, opts dumper.ConnOpts) dumper.Dumper {
+	return &streamingDumper{
+		myDumper: &myDumper{
+			conn:      conn,
+			reader:    rdr,
+			opts:      opts,
+			batch:     opts.Batch,
+			throttler: newReplicaLagThrottler(conn, opts),
+		},
+		opts:   opts,
+		tables: make(map[string]struct{}),
+	}
+}
+
+// DumpStructure runs the normal destination DDL apply, then, on the very first call, snapshots
+// the source's current binlog position (and GTID set, if any) and seeds the state table with it.
+// This must happen before any table is copied so Stream has a real resume point to start from
+// instead of falling back to position zero, which would either fail against the source or start
+// from whatever the server chooses - silently skipping or duplicating rows relative to the bulk
+// copy that's about to run.
+func (d *streamingDumper) DumpStructure(sql string) error {
+	if err := d.myDumper.DumpStructure(sql); err != nil {
+		return err
+	}
+
+	d.seedOnce.Do(func() {
+		d.seedErr = d.seedInitialPosition()
+	})
+	return d.seedErr
+}
+
+// seedInitialPosition reads SHOW MASTER STATUS from the source and stores it as the state
+// table's starting point, unless a previous run already recorded real progress.
+func (d *streamingDumper) seedInitialPosition() error {
+	if err := d.ensureStateTable(); err != nil {
+		return fmt.Errorf("failed to ensure binlog state table: %w", err)
+	}
+
+	existingPos, existingGTID, err := d.loadState()
+	if err != nil {
+		return fmt.Errorf("failed to check existing binlog state: %w", err)
+	}
+	if existingPos.Name != "" || existingGTID != "" {
+		return nil
+	}
+
+	sourceConn, err := sql.Open("mysql", d.opts.SourceDSN)
+	if err != nil {
+		return fmt.Errorf("failed to open source connection for binlog snapshot: %w", err)
+	}
+	defer sourceConn.Close()
+
+	status, ok, err := showRow(sourceConn, "SHOW MASTER STATUS")
+	if err != nil {
+		return fmt.Errorf("failed to read source SHOW MASTER STATUS: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("source returned no rows for SHOW MASTER STATUS; is binary logging enabled?")
+	}
+
+	logPos, err := strconv.ParseUint(status["Position"], 10, 32)
+	if err != nil {
+		return fmt.Errorf("failed to parse source binlog position %q: %w", status["Position"], err)
+	}
+
+	pos := gomysql.Position{Name: status["File"], Pos: uint32(logPos)}
+	gtidSet := status["Executed_Gtid_Set"]
+	if err := d.saveState(pos, gtidSet); err != nil {
+		return fmt.Errorf("failed to seed binlog state: %w", err)
+	}
+
+	log.WithFields(log.Fields{"file": pos.Name, "pos": pos.Pos}).
+		Info("seeded initial binlog position from source SHOW MASTER STATUS")
+	return nil
+}
+
+// DumpTable runs the normal bulk copy (through the configured insert strategy), then remembers tableName so Stream knows which
+// tables to keep applying binlog events for.
+func (d *streamingDumper) DumpTable(tableName string, rowChan <-chan database.Row) error {
+	if err := d.myDumper.DumpTable(tableName, rowChan); err != nil {
+		return err
+	}
+
+	d.tablesMu.Lock()
+	d.tables[tableName] = struct{}{}
+	d.tablesMu.Unlock()
+
+	return nil
+}
+
+// Close stops the binlog syncer, if one was started, before closing the underlying connection.
+func (d *streamingDumper) Close() error {
+	if d.syncer != nil {
+		d.syncer.Close()
+	}
+
+	return d.myDumper.Close()
+}
+
+// Stream connects to the source identified by opts.SourceDSN as a fake replica and applies row
+// events for every table previously seen via DumpTable until ctx is cancelled. It's meant to be
+// called once the initial DumpStructure/DumpTable pass has completed, turning the one-shot copy
+// into a continuously refreshed sanitised replica.
+func (d *streamingDumper) Stream(ctx context.Context) error {
+	if err := d.ensureStateTable(); err != nil {
+		return fmt.Errorf("failed to ensure binlog state table: %w", err)
+	}
+
+	sourceCfg, err := mysql.ParseDSN(d.opts.SourceDSN)
+	if err != nil {
+		return fmt.Errorf("failed to parse source dsn: %w", err)
+	}
+
+	host, port, err := splitHostPort(sourceCfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to parse source address %q: %w", sourceCfg.Addr, err)
+	}
+
+	serverID := d.opts.ServerID
+	if serverID == 0 {
+		serverID = defaultServerID
+	}
+
+	d.syncer = replication.NewBinlogSyncer(replication.BinlogSyncerConfig{
+		ServerID: serverID,
+		Flavor:   "mysql",
+		Host:     host,
+		Port:     port,
+		User:     sourceCfg.User,
+		Password: sourceCfg.Passwd,
+	})
+
+	pos, gtidSet, err := d.loadState()
+	if err != nil {
+		return fmt.Errorf("failed to load binlog state: %w", err)
+	}
+
+	var streamer *replication.BinlogStreamer
+	if gtidSet != "" {
+		set, err := gomysql.ParseMysqlGTIDSet(gtidSet)
+		if err != nil {
+			return fmt.Errorf("failed to parse stored gtid set: %w", err)
+		}
+		streamer, err = d.syncer.StartSyncGTID(set)
+	} else {
+		streamer, err = d.syncer.StartSync(pos)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to start binlog sync: %w", err)
+	}
+
+	for {
+		ev, err := streamer.GetEvent(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read binlog event: %w", err)
+		}
+
+		switch e := ev.Event.(type) {
+		case *replication.RotateEvent:
+			pos = gomysql.Position{Name: string(e.NextLogName), Pos: uint32(e.Position)}
+		case *replication.GTIDEvent:
+			sid, err := uuid.FromBytes(e.SID)
+			if err != nil {
+				return fmt.Errorf("failed to parse gtid event SID: %w", err)
+			}
+			gtidSet, err = addGTID(gtidSet, sid, e.GNO)
+			if err != nil {
+				return fmt.Errorf("failed to advance gtid set: %w", err)
+			}
+		case *replication.RowsEvent:
+			if err := d.applyRowsEvent(ev.Header, e); err != nil {
+				return fmt.Errorf("failed to apply row event: %w", err)
+			}
+		}
+
+		if ev.Header.LogPos > 0 {
+			pos.Pos = ev.Header.LogPos
+		}
+		if err := d.saveState(pos, gtidSet); err != nil {
+			log.WithError(err).Error("failed to persist binlog state")
+		}
+	}
+}
+
+// applyRowsEvent translates a single WRITE_ROWS/UPDATE_ROWS/DELETE_ROWS event into REPLACE INTO
+// / DELETE FROM statements against the destination, anonymising every row along the way. e.Table
+// is already the table map event resolved internally by the syncer for e's TableID, so there's
+// no need to track TableMapEvents ourselves.
+func (d *streamingDumper) applyRowsEvent(h *replication.EventHeader, e *replication.RowsEvent) error {
+	tableName := string(e.Table.Table)
+
+	d.tablesMu.Lock()
+	_, tracked := d.tables[tableName]
+	d.tablesMu.Unlock()
+	if !tracked {
+		return nil
+	}
+
+	columns, err := d.reader.GetColumns(tableName)
+	if err != nil {
+		return fmt.Errorf("failed to get columns for %s: %w", tableName, err)
+	}
+
+	txn, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to open transaction: %w", err)
+	}
+
+	switch h.EventType {
+	case replication.WRITE_ROWS_EVENTv1, replication.WRITE_ROWS_EVENTv2:
+		err = d.applyWrites(txn, tableName, columns, e.Rows)
+	case replication.UPDATE_ROWS_EVENTv1, replication.UPDATE_ROWS_EVENTv2:
+		// update events carry before/after image pairs; only the after image needs applying.
+		err = d.applyWrites(txn, tableName, columns, afterImages(e.Rows))
+	case replication.DELETE_ROWS_EVENTv1, replication.DELETE_ROWS_EVENTv2:
+		err = d.applyDeletes(txn, tableName, columns, e.Rows)
+	}
+
+	if err != nil {
+		if rbErr := txn.Rollback(); rbErr != nil {
+			log.WithError(rbErr).Error("failed to rollback")
+		}
+		return err
+	}
+
+	return txn.Commit()
+}
+
+func (d *streamingDumper) applyWrites(txn *sql.Tx, tableName string, columns []string, rows [][]interface{}) error {
+	for _, values := range rows {
+		row, err := d.rowFromValues(tableName, columns, values)
+		if err != nil {
+			return err
+		}
+
+		rowValues, err := d.valuesFromRow(columns, row)
+		if err != nil {
+			return err
+		}
+
+		query := fmt.Sprintf(
+			"REPLACE INTO %s VALUES (%s)",
+			d.quoteIdentifier(tableName),
+			strings.Join(rowValues, ","),
+		)
+		if _, err := txn.Exec(query); err != nil {
+			return fmt.Errorf("failed to apply replace: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (d *streamingDumper) applyDeletes(txn *sql.Tx, tableName string, columns []string, rows [][]interface{}) error {
+	for _, values := range rows {
+		row, err := d.rowFromValues(tableName, columns, values)
+		if err != nil {
+			return err
+		}
+
+		conds := make([]string, len(columns))
+		for i, col := range columns {
+			v := row[col]
+			if v == nil {
+				conds[i] = fmt.Sprintf("%s IS NULL", d.quoteIdentifier(col))
+				continue
+			}
+
+			literal, err := quoteValue(v)
+			if err != nil {
+				return fmt.Errorf("failed to quote column %q: %w", col, err)
+			}
+			conds[i] = fmt.Sprintf("%s = %s", d.quoteIdentifier(col), literal)
+		}
+
+		query := fmt.Sprintf(
+			"DELETE FROM %s WHERE %s LIMIT 1",
+			d.quoteIdentifier(tableName),
+			strings.Join(conds, " AND "),
+		)
+		if _, err := txn.Exec(query); err != nil {
+			return fmt.Errorf("failed to apply delete: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// rowFromValues maps a binlog row image onto column names and anonymises it through the same
+// reader pipeline used for the initial bulk load.
+func (d *streamingDumper) rowFromValues(tableName string, columns []string, values []interface{}) (database.Row, error) {
+	row := make(database.Row, len(columns))
+	for i, col := range columns {
+		if i < len(values) {
+			row[col] = values[i]
+		}
+	}
+
+	return d.reader.AnonymiseRow(tableName, row)
+}
+
+func afterImages(rows [][]interface{}) [][]interface{} {
+	after := make([][]interface{}, 0, len(rows)/2)
+	for i := 1; i < len(rows); i += 2 {
+		after = append(after, rows[i])
+	}
+	return after
+}
+
+func (d *streamingDumper) ensureStateTable() error {
+	_, err := d.conn.Exec(fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %s (
+			source VARCHAR(255) NOT NULL PRIMARY KEY COMMENT 'host:port/schema, see sourceStateID; never the source DSN',
+			binlog_file VARCHAR(255) NOT NULL DEFAULT '',
+			binlog_pos INT UNSIGNED NOT NULL DEFAULT 0,
+			gtid_set TEXT NOT NULL,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+		)`,
+		d.quoteIdentifier(binlogStateTable),
+	))
+	return err
+}
+
+func (d *streamingDumper) loadState() (gomysql.Position, string, error) {
+	sourceID, err := sourceStateID(d.opts)
+	if err != nil {
+		return gomysql.Position{}, "", err
+	}
+
+	var file, gtidSet string
+	var logPos uint32
+
+	row := d.conn.QueryRow(fmt.Sprintf(
+		"SELECT binlog_file, binlog_pos, gtid_set FROM %s WHERE source = ?",
+		d.quoteIdentifier(binlogStateTable),
+	), sourceID)
+
+	switch err := row.Scan(&file, &logPos, &gtidSet); err {
+	case nil, sql.ErrNoRows:
+		return gomysql.Position{Name: file, Pos: logPos}, gtidSet, nil
+	default:
+		return gomysql.Position{}, "", err
+	}
+}
+
+func (d *streamingDumper) saveState(pos gomysql.Position, gtidSet string) error {
+	sourceID, err := sourceStateID(d.opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.conn.Exec(fmt.Sprintf(
+		`INSERT INTO %s (source, binlog_file, binlog_pos, gtid_set) VALUES (?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE binlog_file = VALUES(binlog_file), binlog_pos = VALUES(binlog_pos), gtid_set = VALUES(gtid_set)`,
+		d.quoteIdentifier(binlogStateTable),
+	), sourceID, pos.Name, pos.Pos, gtidSet)
+	return err
+}
+
+// sourceStateID derives the state table's primary key from opts.SourceDSN: its host:port and
+// schema name, never its user or password, so a table any destination-side reader can see
+// doesn't end up holding source credentials in plaintext.
+func sourceStateID(opts dumper.ConnOpts) (string, error) {
+	cfg, err := mysql.ParseDSN(opts.SourceDSN)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse source dsn: %w", err)
+	}
+
+	return fmt.Sprintf("%s/%s", cfg.Addr, cfg.DBName), nil
+}
+
+// addGTID merges a single transaction's (SID, GNO) into current, the state table's stored GTID
+// set string, and returns the result. An empty current starts a fresh set rather than being
+// parsed, since gomysql.ParseMysqlGTIDSet rejects the empty string.
+func addGTID(current string, sid uuid.UUID, gno int64) (string, error) {
+	set := &gomysql.MysqlGTIDSet{Sets: make(map[string]*gomysql.UUIDSet)}
+	if current != "" {
+		parsed, err := gomysql.ParseMysqlGTIDSet(current)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse current gtid set: %w", err)
+		}
+
+		var ok bool
+		set, ok = parsed.(*gomysql.MysqlGTIDSet)
+		if !ok {
+			return "", fmt.Errorf("unexpected gtid set type %T", parsed)
+		}
+	}
+
+	set.AddSet(&gomysql.UUIDSet{
+		SID:       sid,
+		Intervals: gomysql.IntervalSlice{{Start: gno, Stop: gno + 1}},
+	})
+
+	return set.String(), nil
+}
+
+func splitHostPort(addr string) (string, uint16, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", 0, err
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port %q: %w", portStr, err)
+	}
+
+	return host, uint16(port), nil
+}
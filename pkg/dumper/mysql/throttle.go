@@ -0,0 +1,217 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hellofresh/klepto/pkg/dumper"
+)
+
+// Throttler decides whether DumpTable should pause before copying the next chunk of rows, so a
+// large backfill doesn't overwhelm the source or destination. Modelled after gh-ost's throttler.
+type Throttler interface {
+	// ShouldThrottle reports whether the caller should wait before submitting more work, along
+	// with a human-readable reason suitable for logging.
+	ShouldThrottle() (bool, string, error)
+}
+
+// replicaLagThrottler throttles on the destination's replication lag, a set of SHOW GLOBAL
+// STATUS metric thresholds (e.g. Threads_running), a maximum chunks-per-second rate, and an
+// optional user-provided throttle query.
+type replicaLagThrottler struct {
+	conn          *sql.DB
+	maxLagMillis  int64
+	maxLoad       map[string]int64
+	maxQPS        int
+	throttleQuery string
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowCount int
+}
+
+// newReplicaLagThrottler builds a Throttler from opts. It's always non-nil; with no thresholds
+// configured, ShouldThrottle simply never throttles.
+func newReplicaLagThrottler(conn *sql.DB, opts dumper.ConnOpts) *replicaLagThrottler {
+	return &replicaLagThrottler{
+		conn:          conn,
+		maxLagMillis:  opts.MaxLagMillis,
+		maxLoad:       parseMaxLoad(opts.MaxLoad),
+		maxQPS:        opts.MaxQPS,
+		throttleQuery: opts.ThrottleQuery,
+	}
+}
+
+// parseMaxLoad parses a gh-ost style "Metric=threshold,Metric=threshold" string, e.g.
+// "Threads_running=25,Threads_connected=500", as used by opts.MaxLoad / --max-load.
+func parseMaxLoad(maxLoad string) map[string]int64 {
+	thresholds := make(map[string]int64)
+	for _, pair := range strings.Split(maxLoad, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		metric, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+
+		threshold, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+		thresholds[strings.TrimSpace(metric)] = threshold
+	}
+
+	return thresholds
+}
+
+func (t *replicaLagThrottler) ShouldThrottle() (bool, string, error) {
+	if t.maxLagMillis > 0 {
+		lagMillis, known, err := t.replicationLagMillis()
+		if err != nil {
+			return false, "", fmt.Errorf("failed to read destination replication lag: %w", err)
+		}
+		if known && lagMillis > t.maxLagMillis {
+			return true, fmt.Sprintf("destination lag %dms exceeds max %dms", lagMillis, t.maxLagMillis), nil
+		}
+	}
+
+	for metric, threshold := range t.maxLoad {
+		value, err := t.globalStatus(metric)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to read %s from SHOW GLOBAL STATUS: %w", metric, err)
+		}
+		if value > threshold {
+			return true, fmt.Sprintf("%s=%d exceeds max-load threshold %d", metric, value, threshold), nil
+		}
+	}
+
+	if t.maxQPS > 0 && t.overQPS() {
+		return true, fmt.Sprintf("exceeding max QPS of %d", t.maxQPS), nil
+	}
+
+	if t.throttleQuery != "" {
+		passed, err := t.throttleQueryPasses()
+		if err != nil {
+			return false, "", fmt.Errorf("failed to run throttle query: %w", err)
+		}
+		if !passed {
+			return true, "throttle query did not pass", nil
+		}
+	}
+
+	return false, "", nil
+}
+
+// replicationLagMillis reads Seconds_Behind_Master from SHOW SLAVE STATUS on the destination.
+// known is false when replication isn't configured or the value is NULL (replication stopped).
+func (t *replicaLagThrottler) replicationLagMillis() (int64, bool, error) {
+	status, ok, err := t.showRow("SHOW SLAVE STATUS")
+	if err != nil || !ok {
+		return 0, false, err
+	}
+
+	raw, ok := status["Seconds_Behind_Master"]
+	if !ok || raw == "" {
+		return 0, false, nil
+	}
+
+	seconds, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to parse Seconds_Behind_Master %q: %w", raw, err)
+	}
+
+	return seconds * 1000, true, nil
+}
+
+// globalStatus reads a single metric from SHOW GLOBAL STATUS LIKE 'metric'.
+func (t *replicaLagThrottler) globalStatus(metric string) (int64, error) {
+	var name, value string
+	err := t.conn.QueryRow("SHOW GLOBAL STATUS LIKE ?", metric).Scan(&name, &value)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// throttleQueryPasses runs the user-provided throttle query and treats a missing row, or a
+// falsy/zero first column, as "not safe to proceed" - mirroring gh-ost's throttle-query contract.
+func (t *replicaLagThrottler) throttleQueryPasses() (bool, error) {
+	var value int64
+	err := t.conn.QueryRow(t.throttleQuery).Scan(&value)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return value != 0, nil
+}
+
+// overQPS tracks how many chunks have been submitted in the current one-second window and
+// reports whether that would exceed maxQPS.
+func (t *replicaLagThrottler) overQPS() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(t.windowStart) >= time.Second {
+		t.windowStart = now
+		t.windowCount = 0
+	}
+
+	t.windowCount++
+	return t.windowCount > t.maxQPS
+}
+
+// showRow runs a SHOW-style statement and scans its first row into a column name -> value map.
+// ok is false if the statement returned no rows (e.g. this destination isn't a replica).
+func (t *replicaLagThrottler) showRow(query string) (map[string]string, bool, error) {
+	return showRow(t.conn, query)
+}
+
+// showRow runs a SHOW-style statement against db and scans its first row into a column name ->
+// value map. ok is false if the statement returned no rows.
+func showRow(db *sql.DB, query string) (map[string]string, bool, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !rows.Next() {
+		return nil, false, rows.Err()
+	}
+
+	values := make([]sql.RawBytes, len(columns))
+	scanArgs := make([]interface{}, len(columns))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	if err := rows.Scan(scanArgs...); err != nil {
+		return nil, false, err
+	}
+
+	result := make(map[string]string, len(columns))
+	for i, col := range columns {
+		result[col] = string(values[i])
+	}
+
+	return result, true, nil
+}
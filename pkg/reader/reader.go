@@ -0,0 +1,27 @@
+// Package reader defines the interface used to read and anonymise rows from a source database.
+package reader
+
+import "github.com/hellofresh/klepto/pkg/database"
+
+// Reader reads table structure and rows from a source database, applying the configured
+// anonymisation rules before handing rows to a dumper.
+type Reader interface {
+	// GetColumns returns the ordered column names for the given table.
+	GetColumns(tableName string) ([]string, error)
+	// GetColumnTypes returns the ordered column metadata (type, nullability, charset) for the
+	// given table, so dumpers can encode values without guessing from the Go value alone.
+	GetColumnTypes(tableName string) ([]database.Column, error)
+	// AnonymiseRow runs a single row through the anonymiser pipeline configured for tableName.
+	// It is used by dumpers that need to anonymise rows they did not get from the reader itself,
+	// e.g. rows sourced from a replication stream rather than a SELECT.
+	AnonymiseRow(tableName string, row database.Row) (database.Row, error)
+	// ReadTableChunk returns up to limit already-anonymised rows from tableName, ordered
+	// ascending by key (a primary/unique index's columns, in index order - composite keys are
+	// walked as a tuple so chunking doesn't skip rows that share a leading column value),
+	// starting strictly after the given key tuple (pass nil for the first chunk; otherwise one
+	// value per entry in key, in the same order). It's the gh-ost-style "SELECT ... WHERE
+	// (key[0], key[1], ...) > (:after[0], :after[1], ...) ORDER BY key[0], key[1], ... LIMIT
+	// :limit" read a chunked dumper walks a table with. hasMore reports whether at least one row
+	// exists beyond this chunk, so the caller knows whether to ask for another one.
+	ReadTableChunk(tableName string, key []string, after []interface{}, limit int64) (rows []database.Row, hasMore bool, err error)
+}